@@ -0,0 +1,65 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/svc-excavator-bot-org/outparamcheck/outparamcheck"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flagSet := flag.NewFlagSet("outparamcheck", flag.ExitOnError)
+	configPath := flagSet.String("config", "", "path to a YAML, TOML, or JSON config file of additional out-param functions to check")
+	unused := flagSet.Bool("unused", false, "also report out-param pointers that are never read after the call (requires building SSA, so it is slower)")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	cfg := outparamcheck.DefaultConfig()
+	if *configPath != "" {
+		userCfg, err := outparamcheck.LoadConfig(*configPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		cfg = outparamcheck.Merge(cfg, userCfg)
+	}
+
+	mode := packages.LoadSyntax
+	if *unused {
+		// CheckUnused builds SSA, which needs the full dependency graph.
+		mode = packages.LoadAllSyntax
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: mode,
+	}, flagSet.Args()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	errs := outparamcheck.Run(pkgs, cfg)
+	if *unused {
+		errs = append(errs, outparamcheck.CheckUnused(pkgs, cfg)...)
+	}
+	for _, e := range errs {
+		fmt.Println(e.String())
+	}
+	if len(errs) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// analyzerConfigPath is the value of the Analyzer's -config flag. It is
+// merged into defaultCfg by runAnalysis, mirroring the outparamcheck
+// binary's own -config flag.
+var analyzerConfigPath string
+
+// Analyzer reports calls to known out-parameter functions (such as
+// json.Unmarshal) that are passed a non-pointer argument, so that it can be
+// run via go vet, golangci-lint, or any other x/tools/go/analysis driver.
+var Analyzer = &analysis.Analyzer{
+	Name: "outparamcheck",
+	Doc:  "reports calls to known out-parameter functions (e.g. json.Unmarshal) whose argument is not a pointer",
+	Run:  runAnalysis,
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&analyzerConfigPath, "config", "", "path to a YAML, TOML, or JSON config file of additional out-param functions to check")
+}
+
+// runAnalysis is the analysis.Analyzer.Run implementation backing
+// Analyzer. It walks every file in the pass, reusing the same
+// findOutParams logic as run, and reports each finding as an
+// analysis.Diagnostic with a SuggestedFix for the common mechanical case of
+// a bare addressable expression that was never had its address taken.
+func runAnalysis(pass *analysis.Pass) (interface{}, error) {
+	cfg := defaultCfg
+	if analyzerConfigPath != "" {
+		userCfg, err := LoadConfig(analyzerConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("outparamcheck: %v", err)
+		}
+		cfg = merge(defaultCfg, userCfg)
+	}
+	cfg = merge(cfg, scanMarkersInFiles(pass.Files, pass.TypesInfo))
+
+	for _, file := range pass.Files {
+		for _, f := range findOutParams(pass.TypesInfo, file, cfg) {
+			diag := analysis.Diagnostic{
+				Pos:     f.Arg.Pos(),
+				Message: fmt.Sprintf("argument %d to %s must be a pointer", f.Idx, methodName(f.Key)),
+			}
+			if fix, ok := suggestAddressOf(pass, f.Arg); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+			}
+			pass.Report(diag)
+		}
+	}
+	return nil, nil
+}
+
+// suggestAddressOf proposes replacing arg with &arg when arg's type is
+// addressable and it isn't already of the form &x, *&x, or nil -- the only
+// case where taking the address is a safe, purely mechanical fix.
+func suggestAddressOf(pass *analysis.Pass, arg ast.Expr) (analysis.SuggestedFix, bool) {
+	e := unparen(arg)
+
+	switch v := e.(type) {
+	case *ast.Ident:
+		if v.Name == "nil" {
+			return analysis.SuggestedFix{}, false
+		}
+	case *ast.UnaryExpr, *ast.StarExpr:
+		return analysis.SuggestedFix{}, false
+	}
+
+	tv, ok := pass.TypesInfo.Types[e]
+	if !ok || !tv.Addressable() {
+		return analysis.SuggestedFix{}, false
+	}
+
+	text, ok := exprText(pass, e)
+	if !ok {
+		return analysis.SuggestedFix{}, false
+	}
+
+	return analysis.SuggestedFix{
+		Message: "take the address of the argument",
+		TextEdits: []analysis.TextEdit{
+			{
+				Pos:     arg.Pos(),
+				End:     arg.End(),
+				NewText: append([]byte("&"), text...),
+			},
+		},
+	}, true
+}
+
+// exprText renders e via go/format against pass.Fset, rather than reading
+// its source positions back off disk: the file on disk can have changed or
+// gone missing since pass.Files was parsed (e.g. under a driver with an
+// in-memory overlay), in which case a disk read would silently produce a
+// truncated or empty result and corrupt the suggested fix.
+func exprText(pass *analysis.Pass, e ast.Expr) ([]byte, bool) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, pass.Fset, e); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
@@ -0,0 +1,21 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	// RunWithSuggestedFixes applies every SuggestedFix from the analyzer to
+	// testdata/src/a/a.go and compares the result against a.go.golden, so a
+	// future change to suggestAddressOf/exprText that corrupts the edit
+	// (e.g. reintroducing a stale-disk-read) fails here, not just in a
+	// human review.
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), Analyzer, "a")
+}
@@ -0,0 +1,19 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+// These codec libraries only ever appear as import path strings inside the
+// dynamically-compiled test fixtures in codecs_test.go, so `go mod tidy`
+// has no way to see that this module depends on them. Blank-import them
+// here so they stay in go.mod/go.sum for packages.Load to resolve at test
+// time.
+import (
+	_ "github.com/pelletier/go-toml/v2"
+	_ "github.com/vmihailenco/msgpack/v5"
+	_ "go.mongodb.org/mongo-driver/bson"
+	_ "google.golang.org/protobuf/proto"
+	_ "gopkg.in/yaml.v2"
+)
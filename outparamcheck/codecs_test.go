@@ -0,0 +1,918 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"go/token"
+	"testing"
+)
+
+// TestOutParamCheckCodecs is the table-driven counterpart of
+// TestOutParamCheck, run against each additional codec library that
+// defaultCfg now knows about. Each library gets the same interface, go,
+// defer, and struct literal scenarios as the json.Unmarshal cases in
+// TestOutParamCheck;
+// it does not attempt to repeat every scenario in TestOutParamCheck,
+// since the syntactic check (isAddressable) is shared code and does not
+// vary by library.
+func TestOutParamCheckCodecs(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    string
+		expected []OutParamError
+	}{
+		{
+			name: "yaml_v3",
+			input: `
+			package main
+
+			import (
+				"gopkg.in/yaml.v3"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				yaml.Unmarshal(j, x)
+				yaml.Unmarshal(j, &x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   143,
+						Line:     11,
+						Column:   23,
+					},
+					Line:     `yaml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "yaml_v3/go",
+			input: `
+			package main
+			
+			import (
+				"gopkg.in/yaml.v3"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				go yaml.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   152,
+						Line:     11,
+						Column:   26,
+					},
+					Line:     `go yaml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "yaml_v3/defer",
+			input: `
+			package main
+			
+			import (
+				"gopkg.in/yaml.v3"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				defer yaml.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   155,
+						Line:     11,
+						Column:   29,
+					},
+					Line:     `defer yaml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "yaml_v3/struct literal",
+			input: `
+			package main
+			
+			import (
+				"gopkg.in/yaml.v3"
+			)
+			
+			type errStruct struct {
+				err error
+			}
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				_ = errStruct {
+					err: yaml.Unmarshal(j, x), 
+				}
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   222,
+						Line:     16,
+						Column:   29,
+					},
+					Line:     `err: yaml.Unmarshal(j, x),`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "yaml_v2",
+			input: `
+			package main
+
+			import (
+				"gopkg.in/yaml.v2"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				yaml.Unmarshal(j, x)
+				yaml.Unmarshal(j, &x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   143,
+						Line:     11,
+						Column:   23,
+					},
+					Line:     `yaml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "yaml_v2/go",
+			input: `
+			package main
+			
+			import (
+				"gopkg.in/yaml.v2"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				go yaml.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   152,
+						Line:     11,
+						Column:   26,
+					},
+					Line:     `go yaml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "yaml_v2/defer",
+			input: `
+			package main
+			
+			import (
+				"gopkg.in/yaml.v2"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				defer yaml.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   155,
+						Line:     11,
+						Column:   29,
+					},
+					Line:     `defer yaml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "yaml_v2/struct literal",
+			input: `
+			package main
+			
+			import (
+				"gopkg.in/yaml.v2"
+			)
+			
+			type errStruct struct {
+				err error
+			}
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				_ = errStruct {
+					err: yaml.Unmarshal(j, x), 
+				}
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   222,
+						Line:     16,
+						Column:   29,
+					},
+					Line:     `err: yaml.Unmarshal(j, x),`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "toml_burntsushi",
+			input: `
+			package main
+
+			import (
+				"github.com/BurntSushi/toml"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				toml.Unmarshal(j, x)
+				toml.Unmarshal(j, &x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   153,
+						Line:     11,
+						Column:   23,
+					},
+					Line:     `toml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "toml_burntsushi/go",
+			input: `
+			package main
+			
+			import (
+				"github.com/BurntSushi/toml"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				go toml.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   162,
+						Line:     11,
+						Column:   26,
+					},
+					Line:     `go toml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "toml_burntsushi/defer",
+			input: `
+			package main
+			
+			import (
+				"github.com/BurntSushi/toml"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				defer toml.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   165,
+						Line:     11,
+						Column:   29,
+					},
+					Line:     `defer toml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "toml_burntsushi/struct literal",
+			input: `
+			package main
+			
+			import (
+				"github.com/BurntSushi/toml"
+			)
+			
+			type errStruct struct {
+				err error
+			}
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				_ = errStruct {
+					err: toml.Unmarshal(j, x), 
+				}
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   232,
+						Line:     16,
+						Column:   29,
+					},
+					Line:     `err: toml.Unmarshal(j, x),`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "toml_pelletier",
+			input: `
+			package main
+
+			import (
+				"github.com/pelletier/go-toml/v2"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				toml.Unmarshal(j, x)
+				toml.Unmarshal(j, &x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   158,
+						Line:     11,
+						Column:   23,
+					},
+					Line:     `toml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "toml_pelletier/go",
+			input: `
+			package main
+			
+			import (
+				"github.com/pelletier/go-toml/v2"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				go toml.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   167,
+						Line:     11,
+						Column:   26,
+					},
+					Line:     `go toml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "toml_pelletier/defer",
+			input: `
+			package main
+			
+			import (
+				"github.com/pelletier/go-toml/v2"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				defer toml.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   170,
+						Line:     11,
+						Column:   29,
+					},
+					Line:     `defer toml.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "toml_pelletier/struct literal",
+			input: `
+			package main
+			
+			import (
+				"github.com/pelletier/go-toml/v2"
+			)
+			
+			type errStruct struct {
+				err error
+			}
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				_ = errStruct {
+					err: toml.Unmarshal(j, x), 
+				}
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   237,
+						Line:     16,
+						Column:   29,
+					},
+					Line:     `err: toml.Unmarshal(j, x),`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "bson",
+			input: `
+			package main
+
+			import (
+				"go.mongodb.org/mongo-driver/bson"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				bson.Unmarshal(j, x)
+				bson.Unmarshal(j, &x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   159,
+						Line:     11,
+						Column:   23,
+					},
+					Line:     `bson.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "bson/go",
+			input: `
+			package main
+			
+			import (
+				"go.mongodb.org/mongo-driver/bson"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				go bson.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   168,
+						Line:     11,
+						Column:   26,
+					},
+					Line:     `go bson.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "bson/defer",
+			input: `
+			package main
+			
+			import (
+				"go.mongodb.org/mongo-driver/bson"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				defer bson.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   171,
+						Line:     11,
+						Column:   29,
+					},
+					Line:     `defer bson.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "bson/struct literal",
+			input: `
+			package main
+			
+			import (
+				"go.mongodb.org/mongo-driver/bson"
+			)
+			
+			type errStruct struct {
+				err error
+			}
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				_ = errStruct {
+					err: bson.Unmarshal(j, x), 
+				}
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   238,
+						Line:     16,
+						Column:   29,
+					},
+					Line:     `err: bson.Unmarshal(j, x),`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "msgpack",
+			input: `
+			package main
+
+			import (
+				"github.com/vmihailenco/msgpack/v5"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				msgpack.Unmarshal(j, x)
+				msgpack.Unmarshal(j, &x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   163,
+						Line:     11,
+						Column:   26,
+					},
+					Line:     `msgpack.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "msgpack/go",
+			input: `
+			package main
+			
+			import (
+				"github.com/vmihailenco/msgpack/v5"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				go msgpack.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   172,
+						Line:     11,
+						Column:   29,
+					},
+					Line:     `go msgpack.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "msgpack/defer",
+			input: `
+			package main
+			
+			import (
+				"github.com/vmihailenco/msgpack/v5"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				defer msgpack.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   175,
+						Line:     11,
+						Column:   32,
+					},
+					Line:     `defer msgpack.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "msgpack/struct literal",
+			input: `
+			package main
+			
+			import (
+				"github.com/vmihailenco/msgpack/v5"
+			)
+			
+			type errStruct struct {
+				err error
+			}
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				_ = errStruct {
+					err: msgpack.Unmarshal(j, x), 
+				}
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   242,
+						Line:     16,
+						Column:   32,
+					},
+					Line:     `err: msgpack.Unmarshal(j, x),`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "proto",
+			input: `
+			package main
+
+			import (
+				"google.golang.org/protobuf/proto"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				proto.Unmarshal(j, x)
+				proto.Unmarshal(j, &x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   160,
+						Line:     11,
+						Column:   24,
+					},
+					Line:     `proto.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "proto/go",
+			input: `
+			package main
+			
+			import (
+				"google.golang.org/protobuf/proto"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				go proto.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   169,
+						Line:     11,
+						Column:   27,
+					},
+					Line:     `go proto.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "proto/defer",
+			input: `
+			package main
+			
+			import (
+				"google.golang.org/protobuf/proto"
+			)
+			
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				defer proto.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   172,
+						Line:     11,
+						Column:   30,
+					},
+					Line:     `defer proto.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+
+		{
+			name: "proto/struct literal",
+			input: `
+			package main
+			
+			import (
+				"google.golang.org/protobuf/proto"
+			)
+			
+			type errStruct struct {
+				err error
+			}
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				_ = errStruct {
+					err: proto.Unmarshal(j, x), 
+				}
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   239,
+						Line:     16,
+						Column:   30,
+					},
+					Line:     `err: proto.Unmarshal(j, x),`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+	}
+
+	runTestCases(t, tcs, defaultCfg)
+}
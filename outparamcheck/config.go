@@ -0,0 +1,65 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig reads a user-supplied configuration file at path and returns
+// the Config it describes. The encoding is chosen by the file's extension:
+// ".yaml"/".yml" for YAML, ".toml" for TOML, and ".json" (or no recognized
+// extension) for JSON.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+
+	cfg := Config{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config %s: %v", path, err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML config %s: %v", path, err)
+		}
+	default:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config %s: %v", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Merge returns a new Config containing every entry of base, with every
+// entry of override replacing (not appending to) a base entry of the same
+// key. Neither base nor override is modified.
+func Merge(base, override Config) Config {
+	return merge(base, override)
+}
+
+// merge is the unexported implementation shared by Merge and the package's
+// own tests.
+func merge(base, override Config) Config {
+	merged := make(Config, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,88 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/nmiyake/pkg/dirs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tcs := []struct {
+		name     string
+		filename string
+		content  string
+		expected Config
+	}{
+		{
+			name:     "json",
+			filename: "outparamcheck.json",
+			content: `{
+				"gopkg.in/yaml.v3.Unmarshal": [1]
+			}`,
+			expected: Config{"gopkg.in/yaml.v3.Unmarshal": {1}},
+		},
+		{
+			name:     "yaml",
+			filename: "outparamcheck.yaml",
+			content: `
+gopkg.in/yaml.v3.Unmarshal: [1]
+`,
+			expected: Config{"gopkg.in/yaml.v3.Unmarshal": {1}},
+		},
+		{
+			name:     "toml",
+			filename: "outparamcheck.toml",
+			content: `
+"*database/sql.Row.Scan" = [0, 1, 2]
+`,
+			expected: Config{"*database/sql.Row.Scan": {0, 1, 2}},
+		},
+	}
+
+	tmpDir, cleanup, err := dirs.TempDir(".", "")
+	require.NoError(t, err)
+	defer cleanup()
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			fpath := path.Join(tmpDir, tc.filename)
+			require.NoError(t, ioutil.WriteFile(fpath, []byte(tc.content), 0644))
+
+			cfg, err := LoadConfig(fpath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := Config{
+		"encoding/json.Unmarshal": {1},
+		"encoding/xml.Unmarshal":  {1},
+	}
+	override := Config{
+		"encoding/xml.Unmarshal":    {0},
+		"example.com/custom.Decode": {1},
+	}
+
+	merged := Merge(base, override)
+
+	assert.Equal(t, Config{
+		"encoding/json.Unmarshal":   {1},
+		"encoding/xml.Unmarshal":    {0},
+		"example.com/custom.Decode": {1},
+	}, merged)
+
+	// neither input was mutated.
+	assert.Equal(t, []int{1}, base["encoding/xml.Unmarshal"])
+	assert.Equal(t, []int{0}, override["encoding/xml.Unmarshal"])
+}
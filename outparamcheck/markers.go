@@ -0,0 +1,134 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// markerRegexp matches a "//outparamcheck:outparam N[,N...]" directive in a
+// function's doc comment, where each N is the 0-based index (not counting a
+// method receiver) of an argument that the function treats as an
+// out-parameter.
+var markerRegexp = regexp.MustCompile(`^outparamcheck:outparam\s+(.+)$`)
+
+// scanMarkers walks every function declaration in pkgs looking for an
+// "//outparamcheck:outparam" marker in its doc comment, and returns a
+// Config built from what it finds. This lets library authors annotate
+// their own Unmarshal-like APIs without needing to patch defaultCfg.
+func scanMarkers(pkgs []*packages.Package) Config {
+	cfg := Config{}
+	for _, pkg := range pkgs {
+		for k, v := range scanMarkersInFiles(pkg.Syntax, pkg.TypesInfo) {
+			cfg[k] = v
+		}
+	}
+	return cfg
+}
+
+// scanMarkersInFiles is the shared implementation behind scanMarkers and
+// the Analyzer, which runs over an analysis.Pass's files instead of a
+// packages.Package's. A marker only produces a Config entry for the
+// indices whose parameter type is itself an interface (either interface{},
+// as with json.Unmarshal, or a library's own named Unmarshaler-style
+// interface) -- see isOutParamInterface. An index naming a parameter of
+// some other, already-concrete type is dropped: isAddressable already
+// resolves those against the argument's own static type, so a marker
+// couldn't tell it anything the signature doesn't already say.
+func scanMarkersInFiles(files []*ast.File, info *types.Info) Config {
+	cfg := Config{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Doc == nil {
+				continue
+			}
+			indices, ok := parseMarker(fd.Doc)
+			if !ok {
+				continue
+			}
+			f, ok := info.Defs[fd.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			sig, ok := f.Type().(*types.Signature)
+			if !ok {
+				continue
+			}
+			var qualified []int
+			for _, idx := range indices {
+				if isOutParamInterface(sig, idx) {
+					qualified = append(qualified, idx)
+				}
+			}
+			if len(qualified) == 0 {
+				continue
+			}
+			cfg[funcDeclKey(f)] = qualified
+		}
+	}
+	return cfg
+}
+
+// isOutParamInterface reports whether sig's parameter at idx is declared as
+// an interface type, which is what makes the index worth marking in the
+// first place: an interface-typed parameter accepts both a pointer and a
+// non-pointer argument with no compile error, so the compiler can't catch a
+// caller forgetting "&" the way it would for a concrete pointer parameter.
+func isOutParamInterface(sig *types.Signature, idx int) bool {
+	params := sig.Params()
+	if idx < 0 || idx >= params.Len() {
+		return false
+	}
+	_, ok := params.At(idx).Type().Underlying().(*types.Interface)
+	return ok
+}
+
+// parseMarker extracts the argument indices from an outparamcheck marker in
+// doc, if one is present.
+func parseMarker(doc *ast.CommentGroup) ([]int, bool) {
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		m := markerRegexp.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		var indices []int
+		for _, part := range strings.Split(m[1], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			indices = append(indices, n)
+		}
+		if len(indices) > 0 {
+			return indices, true
+		}
+	}
+	return nil, false
+}
+
+// funcDeclKey returns the Config key for a declared function or method,
+// using the same "import/path.Name" / "*import/path.Type.Name" format as
+// funcKey uses for call sites, so that a marker on the declaration matches
+// the key looked up at every call site.
+func funcDeclKey(f *types.Func) string {
+	sig, ok := f.Type().(*types.Signature)
+	if !ok {
+		return f.Name()
+	}
+	if recv := sig.Recv(); recv != nil {
+		return fmt.Sprintf("*%s.%s", recvTypeName(recv.Type()), f.Name())
+	}
+	return fmt.Sprintf("%s.%s", f.Pkg().Path(), f.Name())
+}
@@ -0,0 +1,72 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"go/token"
+	"testing"
+)
+
+// TestOutParamCheckMarker proves that a local function opts into the check
+// via an "//outparamcheck:outparam" doc comment marker, without needing an
+// entry in any Config.
+func TestOutParamCheckMarker(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    string
+		expected []OutParamError
+	}{
+		{
+			name: "marked function",
+			input: `
+			package main
+
+			type T struct{}
+
+			//outparamcheck:outparam 0
+			func Decode(v interface{}) error {
+				return nil
+			}
+
+			func main() {
+				var t T
+				Decode(t)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   167,
+						Line:     13,
+						Column:   12,
+					},
+					Line:     `Decode(t)`,
+					Method:   "Decode",
+					Argument: 0,
+				},
+			},
+		},
+		{
+			name: "marker on a non-interface parameter is ignored",
+			input: `
+			package main
+
+			//outparamcheck:outparam 0
+			func Decode(v string) error {
+				return nil
+			}
+
+			func main() {
+				Decode("x")
+			}
+			`,
+			expected: nil,
+		},
+	}
+
+	runTestCases(t, tcs, Config{})
+}
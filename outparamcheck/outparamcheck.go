@@ -0,0 +1,273 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+// Package outparamcheck checks Go source for calls to known "out parameter"
+// functions (functions that populate one or more of their arguments, such as
+// json.Unmarshal) where the argument passed at the out-parameter position is
+// not a pointer and therefore cannot be populated by the callee.
+package outparamcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Config maps a fully-qualified function or method name to the list of
+// argument indices (0-based, not counting a method receiver) that the
+// function treats as out-parameters. Keys take the form
+// "import/path.FuncName" for package-level functions, or
+// "*import/path.TypeName.MethodName" for methods declared on a pointer
+// receiver.
+type Config map[string][]int
+
+// defaultCfg is the built-in set of known out-parameter functions from the
+// standard library and other common decoding packages.
+var defaultCfg = Config{
+	"encoding/json.Unmarshal": {1},
+	"encoding/xml.Unmarshal":  {1},
+
+	"gopkg.in/yaml.v3.Unmarshal":       {1},
+	"*gopkg.in/yaml.v3.Decoder.Decode": {0},
+	"gopkg.in/yaml.v2.Unmarshal":       {1},
+	"*gopkg.in/yaml.v2.Decoder.Decode": {0},
+
+	"github.com/BurntSushi/toml.Unmarshal":            {1},
+	"*github.com/BurntSushi/toml.Decoder.Decode":      {0},
+	"github.com/pelletier/go-toml/v2.Unmarshal":       {1},
+	"*github.com/pelletier/go-toml/v2.Decoder.Decode": {0},
+
+	"go.mongodb.org/mongo-driver/bson.Unmarshal":       {1},
+	"*go.mongodb.org/mongo-driver/bson.Decoder.Decode": {0},
+
+	"github.com/vmihailenco/msgpack/v5.Unmarshal":       {1},
+	"*github.com/vmihailenco/msgpack/v5.Decoder.Decode": {0},
+
+	"google.golang.org/protobuf/proto.Unmarshal": {1},
+}
+
+// OutParamErrorKind distinguishes the syntactic check (NotAddressable, the
+// zero value) from the flow-sensitive check run by CheckUnused
+// (UnusedOutParam).
+type OutParamErrorKind int
+
+const (
+	// NotAddressable means the call site passed a non-pointer expression
+	// where a pointer was expected.
+	NotAddressable OutParamErrorKind = iota
+	// UnusedOutParam means the call site passed a pointer, but the
+	// variable it points at is never read on any path after the call.
+	UnusedOutParam
+)
+
+// OutParamError describes a single call site flagged by the checker,
+// either because its out-parameter argument was not addressable
+// (NotAddressable) or because the pointer it did pass is never read
+// afterward (UnusedOutParam).
+type OutParamError struct {
+	Pos      token.Position
+	Line     string
+	Method   string
+	Argument int
+	Kind     OutParamErrorKind
+}
+
+func (e OutParamError) String() string {
+	if e.Kind == UnusedOutParam {
+		return fmt.Sprintf("%s: argument %d to %s is never read after the call: %s", e.Pos, e.Argument, e.Method, e.Line)
+	}
+	return fmt.Sprintf("%s: argument %d to %s must be a pointer: %s", e.Pos, e.Argument, e.Method, e.Line)
+}
+
+// DefaultConfig returns the built-in set of known out-parameter functions.
+func DefaultConfig() Config {
+	return defaultCfg
+}
+
+// Run is the exported entry point used by the outparamcheck binary and by
+// other tools embedding this package. It inspects the loaded packages pkgs
+// for calls to the functions named in cfg and reports every call site where
+// the argument at a configured index is not addressable.
+func Run(pkgs []*packages.Package, cfg Config) []OutParamError {
+	return run(pkgs, cfg)
+}
+
+// finding is a single call site where a configured out-parameter argument
+// was not addressable. It is the shared result of walking a file's AST,
+// consumed both by run (which turns it into an OutParamError) and by
+// runAnalysis (which turns it into an analysis.Diagnostic).
+type finding struct {
+	Key string // config key, e.g. "encoding/json.Unmarshal"
+	Arg ast.Expr
+	Idx int
+}
+
+// run inspects the loaded packages pkgs for calls to the functions named in
+// cfg and reports every call site where the argument at a configured index
+// is not addressable.
+func run(pkgs []*packages.Package, cfg Config) []OutParamError {
+	cfg = merge(cfg, scanMarkers(pkgs))
+
+	var errs []OutParamError
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, f := range findOutParams(pkg.TypesInfo, file, cfg) {
+				errs = append(errs, OutParamError{
+					Pos:      pkg.Fset.Position(f.Arg.Pos()),
+					Line:     sourceLine(pkg.Fset, f.Arg.Pos()),
+					Method:   methodName(f.Key),
+					Argument: f.Idx,
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// findOutParams walks file and returns every call site matching an entry in
+// cfg whose argument at the configured index is not addressable.
+func findOutParams(info *types.Info, file *ast.File, cfg Config) []finding {
+	var findings []finding
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		key, ok := funcKey(info, call)
+		if !ok {
+			return true
+		}
+		indices, ok := cfg[key]
+		if !ok {
+			return true
+		}
+		for _, idx := range indices {
+			if idx < 0 || idx >= len(call.Args) {
+				continue
+			}
+			arg := call.Args[idx]
+			if isAddressable(info, arg) {
+				continue
+			}
+			findings = append(findings, finding{Key: key, Arg: arg, Idx: idx})
+		}
+		return true
+	})
+	return findings
+}
+
+// funcKey returns the configuration key for the function or method called
+// by call, and whether the callee could be resolved.
+func funcKey(info *types.Info, call *ast.CallExpr) (string, bool) {
+	obj := callee(info, call)
+	f, ok := obj.(*types.Func)
+	if !ok {
+		return "", false
+	}
+	sig, ok := f.Type().(*types.Signature)
+	if !ok {
+		return "", false
+	}
+	if recv := sig.Recv(); recv != nil {
+		return fmt.Sprintf("*%s.%s", recvTypeName(recv.Type()), f.Name()), true
+	}
+	return fmt.Sprintf("%s.%s", f.Pkg().Path(), f.Name()), true
+}
+
+// callee resolves the function or method being invoked by call, unwrapping
+// selector expressions.
+func callee(info *types.Info, call *ast.CallExpr) types.Object {
+	var ident *ast.Ident
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fn
+	case *ast.SelectorExpr:
+		ident = fn.Sel
+	default:
+		return nil
+	}
+	return info.Uses[ident]
+}
+
+func recvTypeName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if pkg := obj.Pkg(); pkg != nil {
+			return fmt.Sprintf("%s.%s", pkg.Path(), obj.Name())
+		}
+		return obj.Name()
+	}
+	return t.String()
+}
+
+func methodName(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[i+1:]
+	}
+	return key
+}
+
+// isAddressable reports whether arg should be treated as already pointing
+// at addressable storage: an explicit address-of expression, a dereference
+// of one (a common, if redundant, idiom), a literal nil, or an expression
+// whose static type is already a pointer.
+func isAddressable(info *types.Info, arg ast.Expr) bool {
+	arg = unparen(arg)
+
+	switch e := arg.(type) {
+	case *ast.Ident:
+		if e.Name == "nil" {
+			return true
+		}
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return true
+		}
+	case *ast.StarExpr:
+		return true
+	}
+
+	if tv, ok := info.Types[arg]; ok {
+		if _, ok := tv.Type.Underlying().(*types.Pointer); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func unparen(e ast.Expr) ast.Expr {
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = p.X
+	}
+}
+
+// sourceLine returns the trimmed text of the source line containing pos.
+func sourceLine(fset *token.FileSet, pos token.Pos) string {
+	f := fset.File(pos)
+	position := fset.Position(pos)
+	startOffset := f.Offset(f.LineStart(position.Line))
+
+	data, err := os.ReadFile(position.Filename)
+	if err != nil || startOffset > len(data) {
+		return ""
+	}
+	rest := data[startOffset:]
+	if i := strings.IndexByte(string(rest), '\n'); i >= 0 {
+		rest = rest[:i]
+	}
+	return strings.TrimSpace(string(rest))
+}
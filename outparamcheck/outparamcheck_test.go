@@ -328,6 +328,100 @@ func TestOutParamCheck(t *testing.T) {
 		},
 	}
 
+	runTestCases(t, tcs, defaultCfg)
+}
+
+// TestOutParamCheckCustomConfig proves that a user-supplied Config merges
+// with defaultCfg: entries not present in the override still apply, and an
+// override of an existing key replaces its argument indices rather than
+// appending to them. No case here calls the new example.com/custom.Decode
+// entry; TestMerge checks that merge adds new keys.
+func TestOutParamCheckCustomConfig(t *testing.T) {
+	userCfg := Config{
+		// overrides the builtin entry: only argument 0 is now checked.
+		"encoding/xml.Unmarshal": {0},
+		// a function unknown to defaultCfg.
+		"example.com/custom.Decode": {1},
+	}
+	cfg := merge(defaultCfg, userCfg)
+
+	tcs := []struct {
+		name     string
+		input    string
+		expected []OutParamError
+	}{
+		{
+			// still covered by the unmodified defaultCfg entry.
+			name: "default entry still applies",
+			input: `
+			package main
+
+			import (
+				"encoding/json"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x interface{}
+				json.Unmarshal(j, x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   140,
+						Line:     11,
+						Column:   23,
+					},
+					Line:     `json.Unmarshal(j, x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+				},
+			},
+		},
+		{
+			// overridden entry: argument 1 is no longer checked, argument 0 is.
+			name: "overridden entry uses new indices",
+			input: `
+			package main
+
+			import (
+				"encoding/xml"
+			)
+
+			func main() {
+				var j interface{}
+				d := []byte("...")
+				var x interface{}
+				xml.Unmarshal(d, x)
+				_ = j
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   157,
+						Line:     12,
+						Column:   19,
+					},
+					Line:     `xml.Unmarshal(d, x)`,
+					Method:   "Unmarshal",
+					Argument: 0,
+				},
+			},
+		},
+	}
+
+	runTestCases(t, tcs, cfg)
+}
+
+func runTestCases(t *testing.T, tcs []struct {
+	name     string
+	input    string
+	expected []OutParamError
+}, cfg Config) {
 	tmpDir, cleanup, err := dirs.TempDir(".", "")
 	require.NoError(t, err)
 	defer cleanup()
@@ -353,7 +447,7 @@ func TestOutParamCheck(t *testing.T) {
 		}
 
 		// run out-param checker
-		errs := run(pkgs, defaultCfg)
+		errs := run(pkgs, cfg)
 
 		// assert expectations
 		assert.Equal(t, tc.expected, errs)
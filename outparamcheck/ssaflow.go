@@ -0,0 +1,209 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// CheckUnused runs an intra-procedural flow analysis over pkgs, built on
+// golang.org/x/tools/go/ssa, and reports an OutParamError of kind
+// UnusedOutParam for every call site matching cfg whose pointer argument is
+// never read on any path between the call and the function returning. It
+// is gated behind the binary's -unused flag, since it is more expensive
+// than the syntactic check and the SSA build requires whole-program type
+// information.
+//
+// Only arguments whose type, once unboxed from an interface, is a pointer
+// can reach an *ssa.Alloc here, and the syntactic pass already treats
+// every such argument as addressable (see isAddressable), so this never
+// re-reports a NotAddressable finding: the two checks are complementary,
+// not overlapping.
+func CheckUnused(pkgs []*packages.Package, cfg Config) []OutParamError {
+	cfg = merge(cfg, scanMarkers(pkgs))
+
+	prog, ssaPkgs := ssautil.AllPackages(pkgs, ssa.InstantiateGenerics)
+	prog.Build()
+
+	// ssautil.AllFunctions walks every *ssa.Function reachable from prog,
+	// including methods and function literals -- unlike a package's own
+	// Members, which holds only its package-level functions. Restrict to
+	// functions belonging to one of the packages we were asked to check,
+	// so we don't also walk SSA for every transitive dependency LoadAllSyntax
+	// pulled in.
+	owned := make(map[*ssa.Package]bool, len(ssaPkgs))
+	for _, p := range ssaPkgs {
+		if p != nil {
+			owned[p] = true
+		}
+	}
+
+	var errs []OutParamError
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Blocks == nil || !owned[fn.Pkg] {
+			continue
+		}
+		errs = append(errs, checkFuncUnused(prog.Fset, fn, cfg)...)
+	}
+	return errs
+}
+
+// checkFuncUnused inspects a single SSA function for calls matching cfg
+// whose matched argument's allocation is never read before fn returns.
+func checkFuncUnused(fset *token.FileSet, fn *ssa.Function, cfg Config) []OutParamError {
+	var errs []OutParamError
+	for _, b := range fn.Blocks {
+		for i, instr := range b.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			common := call.Common()
+			callee := common.StaticCallee()
+			if callee == nil || callee.Object() == nil {
+				continue
+			}
+			f, ok := callee.Object().(*types.Func)
+			if !ok {
+				continue
+			}
+			key := funcDeclKey(f)
+			indices, ok := cfg[key]
+			if !ok {
+				continue
+			}
+			args := common.Args
+			if sig, ok := f.Type().(*types.Signature); ok && sig.Recv() != nil {
+				// A static method call passes its receiver as Args[0];
+				// cfg indices do not count the receiver.
+				args = args[1:]
+			}
+			for _, idx := range indices {
+				if idx < 0 || idx >= len(args) {
+					continue
+				}
+				// Only follow arguments that are pointers once interface
+				// boxing is removed. A bare non-pointer x whose address is
+				// taken elsewhere loads from x's Alloc too, but that call
+				// site is the syntactic pass's NotAddressable finding.
+				arg := args[idx]
+				for {
+					if mi, ok := arg.(*ssa.MakeInterface); ok {
+						arg = mi.X
+					} else if ci, ok := arg.(*ssa.ChangeInterface); ok {
+						arg = ci.X
+					} else {
+						break
+					}
+				}
+				if _, ok := arg.Type().Underlying().(*types.Pointer); !ok {
+					continue
+				}
+				alloc := allocFor(args[idx])
+				if alloc == nil {
+					continue
+				}
+				if readAfter(b, i, alloc) {
+					continue
+				}
+				pos := instr.(ssa.Instruction).Pos()
+				errs = append(errs, OutParamError{
+					Pos:      fset.Position(pos),
+					Line:     sourceLine(fset, pos),
+					Method:   methodName(key),
+					Argument: idx,
+					Kind:     UnusedOutParam,
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// allocFor unwraps a pointer SSA value to the *ssa.Alloc it ultimately
+// addresses: through a dereference (the "*&x" idiom), and through the
+// interface-boxing conversion the compiler inserts at call sites like
+// json.Unmarshal(j, &x), where &x is implicitly converted to interface{}.
+func allocFor(v ssa.Value) *ssa.Alloc {
+	switch v := v.(type) {
+	case *ssa.Alloc:
+		return v
+	case *ssa.UnOp:
+		if v.Op == token.MUL {
+			return allocFor(v.X)
+		}
+	case *ssa.MakeInterface:
+		return allocFor(v.X)
+	case *ssa.ChangeInterface:
+		return allocFor(v.X)
+	}
+	return nil
+}
+
+// readAfter performs a forward reachability search over fn's CFG, starting
+// immediately after instruction index afterIdx in block start, looking for
+// a load from alloc before the function returns. If a loop leads back to
+// start, the whole of start is searched, including the instructions
+// before afterIdx.
+func readAfter(start *ssa.BasicBlock, afterIdx int, alloc *ssa.Alloc) bool {
+	visited := make(map[*ssa.BasicBlock]bool)
+
+	var walk func(b *ssa.BasicBlock) bool
+	walk = func(b *ssa.BasicBlock) bool {
+		if visited[b] {
+			return false
+		}
+		visited[b] = true
+
+		for _, instr := range b.Instrs {
+			if readsAlloc(instr, alloc) {
+				return true
+			}
+		}
+		for _, succ := range b.Succs {
+			if walk(succ) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, instr := range start.Instrs[afterIdx+1:] {
+		if readsAlloc(instr, alloc) {
+			return true
+		}
+	}
+	for _, succ := range start.Succs {
+		if walk(succ) {
+			return true
+		}
+	}
+	return false
+}
+
+// readsAlloc reports whether instr loads from, or takes a field/index
+// address rooted at, alloc.
+func readsAlloc(instr ssa.Instruction, alloc *ssa.Alloc) bool {
+	switch i := instr.(type) {
+	case *ssa.UnOp:
+		return i.Op == token.MUL && sameAlloc(i.X, alloc)
+	case *ssa.FieldAddr:
+		return sameAlloc(i.X, alloc)
+	case *ssa.IndexAddr:
+		return sameAlloc(i.X, alloc)
+	}
+	return false
+}
+
+func sameAlloc(v ssa.Value, alloc *ssa.Alloc) bool {
+	a, ok := v.(*ssa.Alloc)
+	return ok && a == alloc
+}
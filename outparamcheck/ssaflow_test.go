@@ -0,0 +1,132 @@
+// Copyright 2013 Kamil Kisiel
+// Modifications copyright 2016 Palantir Technologies, Inc.
+// Licensed under the MIT License. See LICENSE in the project root
+// for license information.
+
+package outparamcheck
+
+import (
+	"go/token"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/nmiyake/pkg/dirs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCheckUnused(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    string
+		expected []OutParamError
+	}{
+		{
+			name: "decoded value never read",
+			input: `
+			package main
+
+			import (
+				"encoding/json"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x map[string]interface{}
+				json.Unmarshal(j, &x)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   147,
+						Line:     11,
+						Column:   19,
+					},
+					Line:     `json.Unmarshal(j, &x)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+					Kind:     UnusedOutParam,
+				},
+			},
+		},
+		{
+			name: "decoded value read afterward",
+			input: `
+			package main
+
+			import (
+				"encoding/json"
+				"fmt"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x map[string]interface{}
+				json.Unmarshal(j, &x)
+				fmt.Println(x)
+			}
+			`,
+			expected: nil,
+		},
+		{
+			name: "decoded through a pointer-to-pointer dereference",
+			input: `
+			package main
+
+			import (
+				"encoding/json"
+			)
+
+			func main() {
+				j := []byte("...")
+				var x map[string]interface{}
+				p := &x
+				pp := &p
+				json.Unmarshal(j, *pp)
+			}
+			`,
+			expected: []OutParamError{
+				{
+					Pos: token.Position{
+						Filename: "", // will be filled in by the test case run
+						Offset:   172,
+						Line:     13,
+						Column:   19,
+					},
+					Line:     `json.Unmarshal(j, *pp)`,
+					Method:   "Unmarshal",
+					Argument: 1,
+					Kind:     UnusedOutParam,
+				},
+			},
+		},
+	}
+
+	tmpDir, cleanup, err := dirs.TempDir(".", "")
+	require.NoError(t, err)
+	defer cleanup()
+
+	for _, tc := range tcs {
+		currCaseDir, err := ioutil.TempDir(tmpDir, "")
+		require.NoError(t, err)
+
+		fpath := path.Join(currCaseDir, "main.go")
+		require.NoError(t, ioutil.WriteFile(fpath, []byte(tc.input), 0644))
+
+		pkgs, err := packages.Load(&packages.Config{
+			Mode: packages.LoadAllSyntax,
+		}, "./"+currCaseDir)
+		require.NoError(t, err)
+
+		for i := range tc.expected {
+			tc.expected[i].Pos.Filename = pkgs[0].GoFiles[0]
+		}
+
+		errs := CheckUnused(pkgs, defaultCfg)
+		assert.Equal(t, tc.expected, errs)
+	}
+}
@@ -0,0 +1,11 @@
+package a
+
+import "encoding/json"
+
+func F() {
+	j := []byte("...")
+	var x interface{}
+	json.Unmarshal(j, x) // want `argument 1 to Unmarshal must be a pointer`
+	json.Unmarshal(j, &x)
+	json.Unmarshal(j, nil)
+}